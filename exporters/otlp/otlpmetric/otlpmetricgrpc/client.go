@@ -23,10 +23,12 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 
 	"go.opentelemetry.io/otel/exporters/otlp/internal/retry"
 	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric"
 	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/internal/oconf"
+	apimetric "go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/sdk/metric"
 	"go.opentelemetry.io/otel/sdk/metric/aggregation"
 	"go.opentelemetry.io/otel/sdk/metric/metricdata"
@@ -66,25 +68,115 @@ type client struct {
 	ourConn bool
 	conn    *grpc.ClientConn
 	msc     colmetricpb.MetricsServiceClient
+
+	// retryConfig builds a fresh retry.RequestFunc for an export when
+	// per-code retry policies are configured, so attempt counts reset for
+	// each export rather than accumulating over the client's lifetime.
+	retryConfig retry.Config
+	// classifier is consulted for every error returned by the collector. It
+	// defaults to retryable, and is overridden by WithRetryClassifier.
+	classifier RetryClassifier
+	// retryPolicies overrides classifier's decision for specific status
+	// codes. It is populated by WithRetryPolicy.
+	retryPolicies map[codes.Code]RetryPolicy
+
+	// partialSuccessHandler, if set via WithPartialSuccessHandler, is
+	// called whenever the collector reports a partial success.
+	partialSuccessHandler PartialSuccessHandler
+	// rejectedDataPoints records the rejected-data-point count from partial
+	// success responses on the exporter's self-observability meter.
+	rejectedDataPoints apimetric.Int64Counter
+
+	// credProvider, if set via WithCredentialProvider, is given a chance to
+	// refresh its credentials and have the export retried once before an
+	// Unauthenticated or PermissionDenied error falls through to requestFunc.
+	credProvider CredentialProvider
+	// dialTarget and baseDialOptions are retained so the client can redial
+	// with freshly obtained credentials after credProvider.Refresh.
+	// baseDialOptions is set whenever a CredentialProvider is configured, but
+	// dialTarget is only set when the client dialed its own conn
+	// (c.ourConn); a conn passed in with WithGRPCConn is not ours to replace.
+	dialTarget      string
+	baseDialOptions []grpc.DialOption
+
+	// compressor is the static compressor name configured with
+	// WithCompressor. It is ignored when adaptive is non-nil.
+	compressor string
+	// adaptive, set by WithAdaptiveCompression, chooses the compressor for
+	// each export based on recent payload sizes instead of using a fixed
+	// compressor.
+	adaptive *adaptiveCompressor
 }
 
 // newClient creates a new gRPC metric client.
 func newClient(ctx context.Context, options ...Option) (otlpmetric.Client, error) {
 	cfg := oconf.NewGRPCConfig(asGRPCOptions(options)...)
 
+	if err := validateCompressor(cfg.Metrics.Compressor); err != nil {
+		return nil, err
+	}
+
+	classifier := retryable
+	if cfg.RetryClassifier != nil {
+		classifier = RetryClassifier(cfg.RetryClassifier)
+	}
+
 	c := &client{
 		exportTimeout: cfg.Metrics.Timeout,
-		requestFunc:   cfg.RetryConfig.RequestFunc(retryable),
+		requestFunc:   cfg.RetryConfig.RequestFunc(classifier),
 		conn:          cfg.GRPCConn,
 
 		temporalitySelector: cfg.Metrics.TemporalitySelector,
 		aggregationSelector: cfg.Metrics.AggregationSelector,
+
+		retryConfig:   cfg.RetryConfig,
+		classifier:    classifier,
+		retryPolicies: fromOconfPolicies(cfg.RetryPolicies),
+
+		partialSuccessHandler: PartialSuccessHandler(cfg.Metrics.PartialSuccessHandler),
+		rejectedDataPoints:    newRejectedDataPointsCounter(),
+
+		compressor: cfg.Metrics.Compressor,
+	}
+
+	if cfg.Metrics.AdaptiveCompression {
+		preferred := c.compressor
+		if preferred == "" {
+			// WithCompressor was not used; give the adaptive compressor
+			// something to fall back to other than "no compression at all".
+			preferred = CompressorGzip
+		}
+		c.adaptive = newAdaptiveCompressor(preferred)
 	}
 
 	if len(cfg.Metrics.Headers) > 0 {
 		c.metadata = metadata.New(cfg.Metrics.Headers)
 	}
 
+	if cfg.CredentialProvider != nil {
+		c.credProvider = CredentialProvider(cfg.CredentialProvider)
+		// Keep the options as configured, without the credentials we are
+		// about to add, so a later reconnect can rebuild DialOptions from
+		// scratch instead of layering credentials on top of credentials.
+		c.baseDialOptions = append([]grpc.DialOption(nil), cfg.DialOptions...)
+
+		tc, err := c.credProvider.TransportCredentials(ctx)
+		if err != nil {
+			return nil, err
+		}
+		prc, err := c.credProvider.PerRPCCredentials(ctx)
+		if err != nil {
+			return nil, err
+		}
+		// These are appended last, not merged with any transport/per-RPC
+		// credentials already in cfg.DialOptions: gRPC applies DialOptions
+		// in order and each one simply overwrites the relevant field, so
+		// appending last is what makes the credential provider win. Do not
+		// also pass WithTLSCredentials/WithPerRPCCredentials alongside
+		// WithCredentialProvider; the provider is meant to be authoritative.
+		cfg.DialOptions = append(cfg.DialOptions, grpc.WithTransportCredentials(tc), grpc.WithPerRPCCredentials(prc))
+	}
+
 	if c.conn == nil {
 		// If the caller did not provide a ClientConn when the client was
 		// created, create one using the configuration they did provide.
@@ -96,6 +188,7 @@ func newClient(ctx context.Context, options ...Option) (otlpmetric.Client, error
 		// it on Shutdown.
 		c.ourConn = true
 		c.conn = conn
+		c.dialTarget = cfg.Metrics.Endpoint
 	}
 
 	c.msc = colmetricpb.NewMetricsServiceClient(c.conn)
@@ -162,10 +255,33 @@ func (c *client) UploadMetrics(ctx context.Context, protoMetrics *metricpb.Resou
 	ctx, cancel := c.exportContext(ctx)
 	defer cancel()
 
-	return c.requestFunc(ctx, func(iCtx context.Context) error {
-		_, err := c.msc.Export(iCtx, &colmetricpb.ExportMetricsServiceRequest{
-			ResourceMetrics: []*metricpb.ResourceMetrics{protoMetrics},
-		})
+	requestFunc := c.requestFunc
+	if len(c.retryPolicies) > 0 {
+		// Build a fresh request func so MaxAttempts is tracked per export
+		// rather than across the client's lifetime.
+		requestFunc = c.retryConfig.RequestFunc(retry.EvaluateFunc(policyClassifier(c.classifier, c.retryPolicies)))
+	}
+
+	return requestFunc(ctx, func(iCtx context.Context) error {
+		resp, err := c.export(iCtx, protoMetrics)
+		c.handlePartialSuccess(iCtx, resp)
+
+		if err != nil && c.credProvider != nil && isAuthError(err) {
+			// Give the credential provider a chance to rotate its
+			// certificate or token. A rotated bearer token takes effect on
+			// the next RPC over the existing conn, but a rotated mTLS
+			// transport certificate does not: gRPC negotiates transport
+			// credentials once per conn, so picking it up requires
+			// redialing. reconnect is a no-op (and returns an error) when
+			// the client doesn't own its conn, e.g. one supplied via
+			// WithGRPCConn.
+			if refreshErr := c.credProvider.Refresh(iCtx); refreshErr == nil {
+				_ = c.reconnect(iCtx)
+				resp, err = c.export(iCtx, protoMetrics)
+				c.handlePartialSuccess(iCtx, resp)
+			}
+		}
+
 		// nil is converted to OK.
 		if status.Code(err) == codes.OK {
 			// Success.
@@ -175,6 +291,64 @@ func (c *client) UploadMetrics(ctx context.Context, protoMetrics *metricpb.Resou
 	})
 }
 
+// reconnect redials the collector using freshly obtained credentials from
+// credProvider, replacing c.conn and c.msc. This is what allows a rotated
+// mTLS transport certificate to actually take effect: gRPC negotiates
+// transport credentials once when a conn is established, so simply calling
+// credProvider.Refresh does not change what an already-established conn is
+// using.
+//
+// It does nothing, and returns an error, if the client does not own its
+// conn (e.g. one supplied via WithGRPCConn) or has no CredentialProvider
+// configured.
+func (c *client) reconnect(ctx context.Context) error {
+	if c.credProvider == nil || !c.ourConn || c.dialTarget == "" {
+		return errNoReconnect
+	}
+
+	tc, err := c.credProvider.TransportCredentials(ctx)
+	if err != nil {
+		return err
+	}
+	prc, err := c.credProvider.PerRPCCredentials(ctx)
+	if err != nil {
+		return err
+	}
+
+	dialOptions := append([]grpc.DialOption(nil), c.baseDialOptions...)
+	dialOptions = append(dialOptions, grpc.WithTransportCredentials(tc), grpc.WithPerRPCCredentials(prc))
+
+	conn, err := grpc.DialContext(ctx, c.dialTarget, dialOptions...)
+	if err != nil {
+		return err
+	}
+
+	old := c.conn
+	c.conn = conn
+	c.msc = colmetricpb.NewMetricsServiceClient(conn)
+	return old.Close()
+}
+
+// export issues a single unary Export RPC for protoMetrics.
+func (c *client) export(ctx context.Context, protoMetrics *metricpb.ResourceMetrics) (*colmetricpb.ExportMetricsServiceResponse, error) {
+	req := &colmetricpb.ExportMetricsServiceRequest{
+		ResourceMetrics: []*metricpb.ResourceMetrics{protoMetrics},
+	}
+
+	compressor := c.compressor
+	size := proto.Size(req)
+	if c.adaptive != nil {
+		compressor = c.adaptive.next(size)
+	}
+
+	start := time.Now()
+	resp, err := c.msc.Export(ctx, req, compressorCallOptions(compressor)...)
+	if c.adaptive != nil {
+		c.adaptive.record(size, time.Since(start))
+	}
+	return resp, err
+}
+
 // exportContext returns a copy of parent with an appropriate deadline and
 // cancellation function based on the clients configured export timeout.
 //
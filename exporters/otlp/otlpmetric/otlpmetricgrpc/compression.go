@@ -0,0 +1,222 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlpmetricgrpc // import "go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding"
+	_ "google.golang.org/grpc/encoding/gzip" // Registers the gzip compressor.
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/internal/oconf"
+)
+
+// Known compressor names accepted by WithCompressor. "zstd" and "snappy"
+// only take effect once a codec for that name has been registered with
+// encoding.RegisterCompressor, either by this package (see
+// RegisterCompressor) or by importing a codec package that registers
+// itself, such as google.golang.org/grpc/encoding/gzip does for "gzip".
+const (
+	CompressorGzip   = "gzip"
+	CompressorZstd   = "zstd"
+	CompressorSnappy = "snappy"
+	CompressorNone   = "none"
+)
+
+// RegisterCompressor registers c under the name returned by c.Name(), so it
+// can subsequently be selected with WithCompressor(c.Name()). The name
+// argument is not used to register c. It is a thin wrapper over
+// encoding.RegisterCompressor that exists so callers don't need to import
+// gRPC's encoding package directly. Compressors are global to the process;
+// call this during initialization, before creating an exporter.
+func RegisterCompressor(name string, c encoding.Compressor) {
+	encoding.RegisterCompressor(c)
+	_ = name // encoding.Compressor already carries its own Name().
+}
+
+// WithCompressor configures the compressor used for every Export call. When
+// WithAdaptiveCompression is also used, name is only the compressor chosen
+// for exports that adaptive compression does not downgrade to none. name
+// must be "none" or the name of a codec registered with
+// encoding.RegisterCompressor: "gzip" always qualifies, since this package
+// imports gRPC's gzip codec for its side effect; "zstd" and "snappy" only
+// qualify once such a codec has been registered (see RegisterCompressor),
+// since this module does not vendor either compression algorithm.
+//
+// newClient validates name against the registry and returns an error if it
+// names a compressor that was never registered, rather than accepting it
+// and letting every subsequent Export fail with Unimplemented.
+func WithCompressor(name string) Option {
+	return wrappedOption{oconf.WithCompressor(name)}
+}
+
+// validateCompressor returns an error if name is neither "", "none", nor
+// the name of a compressor registered with encoding.RegisterCompressor.
+func validateCompressor(name string) error {
+	if name == "" || name == CompressorNone {
+		return nil
+	}
+	if encoding.GetCompressor(name) == nil {
+		return fmt.Errorf("otlpmetricgrpc: compressor %q is not registered; call RegisterCompressor (or import a package that registers it) before using WithCompressor", name)
+	}
+	return nil
+}
+
+// WithAdaptiveCompression enables automatic compressor selection. The
+// client tracks payload size and round-trip latency for recent exports and
+// downgrades to CompressorNone when (a) payloads have been averaging under
+// a fixed size threshold (adaptiveSizeThreshold, 1024 bytes; no option
+// changes it) and (b) recent throughput shows the link isn't the
+// bottleneck, or there are not yet enough samples of differing sizes to
+// estimate throughput -- conditions under which the CPU cost of
+// compressing is assumed to outweigh the bandwidth it would save. It
+// switches back to the compressor configured via WithCompressor, or gzip if
+// none was given, once either condition no longer holds.
+func WithAdaptiveCompression() Option {
+	return wrappedOption{oconf.WithAdaptiveCompression()}
+}
+
+// adaptiveSampleWindow is the number of recent exports the adaptive
+// compressor bases its decision on.
+const adaptiveSampleWindow = 20
+
+// adaptiveSizeThreshold is the payload size, in bytes, below which adaptive
+// compression considers downgrading to no compression.
+const adaptiveSizeThreshold = 1024
+
+// adaptiveBandwidthFloor is the recent throughput, in bytes/sec, above
+// which the link is considered fast enough that compressing a small
+// payload isn't worth its CPU cost. Below this, even small payloads are
+// still compressed because transfer time dominates.
+const adaptiveBandwidthFloor = 10 * 1024 * 1024
+
+// compressionSample is a single (payload size, round-trip latency)
+// observation used to drive the adaptive compressor's decision.
+type compressionSample struct {
+	size int
+	rtt  time.Duration
+}
+
+// adaptiveCompressor tracks recent export sizes and round-trip latencies
+// and picks a compressor accordingly when WithAdaptiveCompression is
+// enabled.
+type adaptiveCompressor struct {
+	mu             sync.Mutex
+	samples        []compressionSample
+	window         int
+	sizeThreshold  int
+	bandwidthFloor float64
+	preferred      string
+}
+
+func newAdaptiveCompressor(preferred string) *adaptiveCompressor {
+	return &adaptiveCompressor{
+		window:         adaptiveSampleWindow,
+		sizeThreshold:  adaptiveSizeThreshold,
+		bandwidthFloor: adaptiveBandwidthFloor,
+		preferred:      preferred,
+	}
+}
+
+// record adds a sample for a just-completed export of size bytes that took
+// rtt to complete.
+func (a *adaptiveCompressor) record(size int, rtt time.Duration) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.samples = append(a.samples, compressionSample{size: size, rtt: rtt})
+	if len(a.samples) > a.window {
+		a.samples = a.samples[len(a.samples)-a.window:]
+	}
+}
+
+// next returns the compressor name to use for the next export of size
+// bytes, based on the recent sample window.
+func (a *adaptiveCompressor) next(size int) string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var totalSize int
+	for _, s := range a.samples {
+		totalSize += s.size
+	}
+	avgSize := (totalSize + size) / (len(a.samples) + 1)
+
+	if avgSize >= a.sizeThreshold {
+		return a.preferred
+	}
+
+	bandwidth, ok := a.estimateBandwidth()
+	if ok && bandwidth <= a.bandwidthFloor {
+		// The link itself is the bottleneck: keep compressing even small
+		// payloads, since transfer time still dominates.
+		return a.preferred
+	}
+	// Either the link is fast enough that compression overhead isn't worth
+	// it, or there isn't yet enough evidence to say otherwise; default to
+	// not paying the CPU cost.
+	return CompressorNone
+}
+
+// estimateBandwidth approximates sustained transfer bandwidth, in
+// bytes/sec, from the recent sample window. A single sample's total
+// round-trip time is dominated by fixed costs (network latency, server
+// processing) rather than the time spent transferring its bytes, so
+// total-size/total-rtt conflates the two and systematically understates
+// bandwidth. Instead, this compares the smallest and largest payloads in
+// the window: the difference in their round-trip times isolates the
+// marginal cost of the additional bytes, which is what bandwidth should
+// describe.
+//
+// ok is false when the window doesn't have enough size variance to isolate
+// that marginal cost (fewer than two samples, or all samples the same
+// size, or a non-increasing RTT for the larger payload).
+func (a *adaptiveCompressor) estimateBandwidth() (bandwidth float64, ok bool) {
+	if len(a.samples) < 2 {
+		return 0, false
+	}
+
+	min, max := a.samples[0], a.samples[0]
+	for _, s := range a.samples[1:] {
+		if s.size < min.size {
+			min = s
+		}
+		if s.size > max.size {
+			max = s
+		}
+	}
+
+	sizeDelta := max.size - min.size
+	rttDelta := max.rtt - min.rtt
+	if sizeDelta <= 0 || rttDelta <= 0 {
+		return 0, false
+	}
+
+	return float64(sizeDelta) / rttDelta.Seconds(), true
+}
+
+// compressorCallOptions returns the grpc.CallOption(s) needed to select
+// name for a single Export call. It is empty for CompressorNone/the empty
+// string, leaving the call to use whatever default the ClientConn was
+// dialed with.
+func compressorCallOptions(name string) []grpc.CallOption {
+	if name == "" || name == CompressorNone {
+		return nil
+	}
+	return []grpc.CallOption{grpc.UseCompressor(name)}
+}
@@ -0,0 +1,124 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlpmetricgrpc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdaptiveCompressorNoSamplesUsesSizeOnly(t *testing.T) {
+	a := newAdaptiveCompressor(CompressorGzip)
+
+	assert.Equal(t, CompressorNone, a.next(10), "small payload with no history should not pay the compression cost")
+	assert.Equal(t, CompressorGzip, a.next(adaptiveSizeThreshold+1), "a large payload should use the preferred compressor")
+}
+
+func TestAdaptiveCompressorStaysCompressedOnSlowLink(t *testing.T) {
+	a := newAdaptiveCompressor(CompressorGzip)
+
+	// Two differently sized samples let estimateBandwidth isolate the
+	// marginal per-byte cost from the fixed latency all samples share. The
+	// extra 850 bytes here cost an extra 10ms, ~85KB/s: a slow link where
+	// transfer time still dominates, so compression should stay on even
+	// for small payloads.
+	a.record(50, 5*time.Millisecond)
+	a.record(900, 15*time.Millisecond)
+
+	assert.Equal(t, CompressorGzip, a.next(100), "a slow link should keep compressing even small payloads")
+}
+
+func TestAdaptiveCompressorDowngradesOnFastLink(t *testing.T) {
+	a := newAdaptiveCompressor(CompressorGzip)
+
+	// Same size spread as the slow-link case, but the extra 850 bytes only
+	// cost an extra 0.5us: a fast link (~1.7GB/s) where the link isn't the
+	// bottleneck, so compression CPU cost isn't worth paying for a small
+	// payload.
+	a.record(50, 10*time.Microsecond)
+	a.record(900, 10*time.Microsecond+500*time.Nanosecond)
+
+	assert.Equal(t, CompressorNone, a.next(100))
+}
+
+func TestAdaptiveCompressorEstimateBandwidthNeedsSizeVariance(t *testing.T) {
+	a := newAdaptiveCompressor(CompressorGzip)
+	a.record(100, time.Microsecond)
+	a.record(100, 2*time.Microsecond) // same size as above: no variance
+
+	_, ok := a.estimateBandwidth()
+	assert.False(t, ok, "identical payload sizes cannot isolate a per-byte transfer cost")
+}
+
+func TestAdaptiveCompressorEstimateBandwidthNeedsAtLeastTwoSamples(t *testing.T) {
+	a := newAdaptiveCompressor(CompressorGzip)
+	a.record(100, time.Microsecond)
+
+	_, ok := a.estimateBandwidth()
+	assert.False(t, ok)
+}
+
+func TestAdaptiveCompressorKeepsCompressingLargePayloads(t *testing.T) {
+	a := newAdaptiveCompressor(CompressorGzip)
+
+	for i := 0; i < 5; i++ {
+		a.record(1, time.Microsecond) // fast link
+	}
+
+	assert.Equal(t, CompressorGzip, a.next(adaptiveSizeThreshold*10), "large payloads should compress regardless of link speed")
+}
+
+func TestValidateCompressorAcceptsEmptyAndNone(t *testing.T) {
+	assert.NoError(t, validateCompressor(""))
+	assert.NoError(t, validateCompressor(CompressorNone))
+}
+
+func TestValidateCompressorAcceptsRegisteredCodec(t *testing.T) {
+	assert.NoError(t, validateCompressor(CompressorGzip), "gzip is registered via this package's blank import")
+}
+
+func TestValidateCompressorRejectsUnregisteredName(t *testing.T) {
+	assert.Error(t, validateCompressor(CompressorSnappy), "snappy is not registered unless the caller imports a codec for it")
+	assert.Error(t, validateCompressor("not-a-real-compressor"))
+}
+
+func TestCompressorCallOptionsOmittedForNone(t *testing.T) {
+	assert.Empty(t, compressorCallOptions(""))
+	assert.Empty(t, compressorCallOptions(CompressorNone))
+	assert.Len(t, compressorCallOptions(CompressorGzip), 1)
+}
+
+func BenchmarkAdaptiveCompressorNext(b *testing.B) {
+	a := newAdaptiveCompressor(CompressorGzip)
+	for i := 0; i < adaptiveSampleWindow; i++ {
+		a.record(2048, 2*time.Millisecond)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		a.next(2048)
+	}
+}
+
+func BenchmarkAdaptiveCompressorRecord(b *testing.B) {
+	a := newAdaptiveCompressor(CompressorGzip)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		a.record(2048, 2*time.Millisecond)
+	}
+}
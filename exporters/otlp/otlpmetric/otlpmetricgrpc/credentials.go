@@ -0,0 +1,81 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlpmetricgrpc // import "go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/internal/oconf"
+)
+
+// errNoReconnect is returned by client.reconnect when it cannot redial,
+// because either no CredentialProvider is configured or the client does not
+// own its conn (e.g. one supplied via WithGRPCConn).
+var errNoReconnect = errors.New("otlpmetricgrpc: client cannot reconnect without an owned conn and a CredentialProvider")
+
+// CredentialProvider supplies gRPC transport and per-RPC credentials on
+// demand, allowing them to be rotated without restarting the exporter. It
+// is used with WithCredentialProvider to support short-lived mTLS
+// certificates, OIDC/JWT bearer tokens, and cloud IAM token rotation.
+type CredentialProvider interface {
+	// TransportCredentials returns the credentials to dial the collector
+	// with. It is called when the client establishes its connection, and
+	// again on every redial performed after Refresh to pick up a rotated
+	// mTLS certificate: gRPC negotiates transport credentials once per
+	// conn, so a live conn does not observe a later certificate change.
+	TransportCredentials(ctx context.Context) (credentials.TransportCredentials, error)
+	// PerRPCCredentials returns the credentials attached to every RPC. It
+	// is called when the client establishes its connection and again on
+	// redial, mirroring TransportCredentials.
+	PerRPCCredentials(ctx context.Context) (credentials.PerRPCCredentials, error)
+	// Refresh is called when the collector rejects a request with
+	// Unauthenticated or PermissionDenied, so the provider can rotate its
+	// underlying certificate or token before the client retries. If the
+	// client owns its conn, it redials after Refresh succeeds so a rotated
+	// transport certificate takes effect immediately instead of on the
+	// next connection attempt gRPC makes on its own.
+	Refresh(ctx context.Context) error
+}
+
+// WithCredentialProvider configures the client to source its transport and
+// per-RPC credentials from p instead of the static options (WithTLSCredentials,
+// WithPerRPCCredentials). Do not combine this with those options: gRPC
+// applies DialOptions in order and the last one to set a given credential
+// wins, and the client appends the provider's credentials last specifically
+// so the provider is authoritative.
+//
+// On an Unauthenticated or PermissionDenied response the client calls
+// p.Refresh, redials to pick up any rotated transport certificate (only
+// possible when the client dialed its own conn, not one passed via
+// WithGRPCConn), and retries the export once before falling back to its
+// configured retry/backoff path.
+func WithCredentialProvider(p CredentialProvider) Option {
+	return wrappedOption{oconf.WithCredentialProvider(oconf.CredentialProvider(p))}
+}
+
+// isAuthError reports whether err represents a failure that a
+// CredentialProvider refresh might resolve.
+func isAuthError(err error) bool {
+	switch statusCode(err) {
+	case codes.Unauthenticated, codes.PermissionDenied:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlpmetricgrpc // import "go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+
+import (
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/internal/oconf"
+)
+
+// Option applies an option to the gRPC driver.
+type Option interface {
+	applyGRPCOption(*oconf.GRPCConfig)
+}
+
+// No WithStreamingExport option is offered here. A client-side streaming
+// export would need a bidirectional-streaming RPC on the collector, but
+// MetricsServiceClient.Export (go.opentelemetry.io/proto/otlp/collector/metrics/v1)
+// is unary only -- there is no streaming method to call. Supporting it would
+// require a change to the OTLP proto itself, which is out of scope for this
+// exporter; an earlier attempt that funneled exports through the unary
+// Export one at a time without addressing this was removed rather than
+// shipped as a misleading wrapper.
+
+func asGRPCOptions(opts []Option) []oconf.GRPCOption {
+	converted := make([]oconf.GRPCOption, len(opts))
+	for i, o := range opts {
+		converted[i] = oconf.NewGRPCOption(o.applyGRPCOption)
+	}
+	return converted
+}
+
+type wrappedOption struct {
+	oconf.GRPCOption
+}
+
+func (w wrappedOption) applyGRPCOption(cfg *oconf.GRPCConfig) {
+	w.GRPCOption.ApplyGRPCOption(cfg)
+}
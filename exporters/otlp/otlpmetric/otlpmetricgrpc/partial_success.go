@@ -0,0 +1,80 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlpmetricgrpc // import "go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+
+import (
+	"context"
+
+	colmetricpb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/internal/oconf"
+	apimetric "go.opentelemetry.io/otel/metric"
+)
+
+// selfObservabilityScope identifies the meter used to record the exporter's
+// own operational metrics.
+const selfObservabilityScope = "go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+
+// PartialSuccessHandler is invoked whenever the collector reports that it
+// rejected some of the data points in an export, either via a non-zero
+// rejected count or a non-empty error message.
+type PartialSuccessHandler func(rejectedDataPoints int64, message string)
+
+// WithPartialSuccessHandler registers a handler that is called whenever the
+// collector responds to an Export with a partial success. Regardless of
+// whether a handler is configured, the number of rejected data points is
+// also recorded on the exporter's self-observability meter as
+// otlp.exporter.rejected_data_points.
+func WithPartialSuccessHandler(h PartialSuccessHandler) Option {
+	return wrappedOption{oconf.WithPartialSuccessHandler(oconf.PartialSuccessHandler(h))}
+}
+
+// handlePartialSuccess reports any partial success carried by resp to the
+// configured handler and the rejected-data-points counter.
+func (c *client) handlePartialSuccess(ctx context.Context, resp *colmetricpb.ExportMetricsServiceResponse) {
+	ps := resp.GetPartialSuccess()
+	if ps == nil {
+		return
+	}
+
+	rejected := ps.GetRejectedDataPoints()
+	msg := ps.GetErrorMessage()
+	if rejected == 0 && msg == "" {
+		return
+	}
+
+	if c.rejectedDataPoints != nil {
+		c.rejectedDataPoints.Add(ctx, rejected)
+	}
+
+	if c.partialSuccessHandler != nil {
+		c.partialSuccessHandler(rejected, msg)
+	}
+}
+
+// newRejectedDataPointsCounter creates the self-observability instrument
+// used to track data points the collector reports as rejected. Errors
+// creating the instrument are non-fatal: the client still functions, it
+// just won't emit this metric.
+func newRejectedDataPointsCounter() apimetric.Int64Counter {
+	meter := otel.GetMeterProvider().Meter(selfObservabilityScope)
+	counter, _ := meter.Int64Counter(
+		"otlp.exporter.rejected_data_points",
+		apimetric.WithDescription("Number of data points rejected by the collector via a partial success response"),
+		apimetric.WithUnit("{datapoint}"),
+	)
+	return counter
+}
@@ -0,0 +1,85 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlpmetricgrpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	colmetricpb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+)
+
+func TestHandlePartialSuccessIgnoresNilResponse(t *testing.T) {
+	c := &client{}
+	c.handlePartialSuccess(context.Background(), nil) // must not panic
+}
+
+func TestHandlePartialSuccessIgnoresEmptyPartialSuccess(t *testing.T) {
+	var called bool
+	c := &client{partialSuccessHandler: func(int64, string) { called = true }}
+
+	resp := &colmetricpb.ExportMetricsServiceResponse{
+		PartialSuccess: &colmetricpb.ExportMetricsPartialSuccess{},
+	}
+	c.handlePartialSuccess(context.Background(), resp)
+
+	assert.False(t, called, "a zero-value partial success should not invoke the handler")
+}
+
+func TestHandlePartialSuccessReportsRejectedDataPoints(t *testing.T) {
+	var gotRejected int64
+	var gotMsg string
+	c := &client{partialSuccessHandler: func(rejected int64, msg string) {
+		gotRejected = rejected
+		gotMsg = msg
+	}}
+
+	resp := &colmetricpb.ExportMetricsServiceResponse{
+		PartialSuccess: &colmetricpb.ExportMetricsPartialSuccess{
+			RejectedDataPoints: 7,
+			ErrorMessage:       "collector dropped some points",
+		},
+	}
+	c.handlePartialSuccess(context.Background(), resp)
+
+	assert.EqualValues(t, 7, gotRejected)
+	assert.Equal(t, "collector dropped some points", gotMsg)
+}
+
+func TestHandlePartialSuccessReportsMessageOnlyFailure(t *testing.T) {
+	var gotMsg string
+	c := &client{partialSuccessHandler: func(_ int64, msg string) { gotMsg = msg }}
+
+	resp := &colmetricpb.ExportMetricsServiceResponse{
+		PartialSuccess: &colmetricpb.ExportMetricsPartialSuccess{
+			ErrorMessage: "endpoint is shutting down",
+		},
+	}
+	c.handlePartialSuccess(context.Background(), resp)
+
+	assert.Equal(t, "endpoint is shutting down", gotMsg)
+}
+
+func TestHandlePartialSuccessWithoutHandlerDoesNotPanic(t *testing.T) {
+	c := &client{}
+
+	resp := &colmetricpb.ExportMetricsServiceResponse{
+		PartialSuccess: &colmetricpb.ExportMetricsPartialSuccess{
+			RejectedDataPoints: 3,
+		},
+	}
+	c.handlePartialSuccess(context.Background(), resp)
+}
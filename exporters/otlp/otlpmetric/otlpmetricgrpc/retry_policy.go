@@ -0,0 +1,147 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlpmetricgrpc // import "go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+
+import (
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/internal/oconf"
+)
+
+func statusCode(err error) codes.Code {
+	return status.Convert(err).Code()
+}
+
+// fromOconfPolicies converts the internal oconf representation set by
+// WithRetryPolicy back into the public RetryPolicy type used by the client.
+func fromOconfPolicies(policies map[codes.Code]oconf.RetryPolicy) map[codes.Code]RetryPolicy {
+	if len(policies) == 0 {
+		return nil
+	}
+	converted := make(map[codes.Code]RetryPolicy, len(policies))
+	for code, p := range policies {
+		converted[code] = RetryPolicy{
+			Retry:       p.Retry,
+			MaxAttempts: p.MaxAttempts,
+			Multiplier:  p.Multiplier,
+		}
+	}
+	return converted
+}
+
+// RetryClassifier decides whether an error returned by the collector should
+// be retried and, if so, how long the client should wait before making the
+// next attempt. It replaces the package's built-in classification of gRPC
+// status codes when supplied via WithRetryClassifier.
+type RetryClassifier func(err error) (retry bool, throttle time.Duration)
+
+// RetryPolicy configures how errors carrying a particular gRPC status code
+// are retried. It is used with WithRetryPolicy to override the default
+// classification for individual codes without replacing the classifier
+// entirely.
+type RetryPolicy struct {
+	// Retry indicates whether requests failing with this code should be
+	// retried at all. Set to false to treat the code as fatal.
+	Retry bool
+	// MaxAttempts bounds the number of attempts made for this code,
+	// including the first. Zero means the overall RetryConfig governs how
+	// long retries continue.
+	MaxAttempts int
+	// Multiplier scales the server-provided throttle delay (from a
+	// RetryInfo detail on the error) used when retrying this code. It does
+	// not affect the exponential backoff interval computed by the client's
+	// overall RetryConfig, which this package does not have the means to
+	// parameterize per code. Zero means no scaling is applied.
+	Multiplier float64
+}
+
+// WithRetryClassifier overrides the client's default retryable-error
+// classification with c. Server-provided throttle hints (RetryInfo) are
+// still honored by the default classifier; a custom RetryClassifier that
+// wants the same behavior should consult throttleDelay-equivalent details
+// on the error itself.
+func WithRetryClassifier(c RetryClassifier) Option {
+	return wrappedOption{oconf.WithRetryClassifier(oconf.RetryClassifier(c))}
+}
+
+// WithRetryPolicy configures per-status-code retry behavior. It composes
+// with the default (or custom, if WithRetryClassifier is also set)
+// classifier: a code with no entry in policies falls back to that
+// classifier's decision.
+//
+// Per-code policies bound attempt count (MaxAttempts) and can force a code
+// fatal or retryable (Retry) regardless of what the underlying classifier
+// would have decided. They do not give each code its own exponential
+// backoff interval or jitter: the backoff schedule itself still comes from
+// the client's single overall RetryConfig, and Multiplier only scales the
+// server-provided RetryInfo throttle hint, not that schedule.
+func WithRetryPolicy(policies map[codes.Code]RetryPolicy) Option {
+	converted := make(map[codes.Code]oconf.RetryPolicy, len(policies))
+	for code, p := range policies {
+		converted[code] = oconf.RetryPolicy{
+			Retry:       p.Retry,
+			MaxAttempts: p.MaxAttempts,
+			Multiplier:  p.Multiplier,
+		}
+	}
+	return wrappedOption{oconf.WithRetryPolicies(converted)}
+}
+
+// policyClassifier builds a RetryClassifier that consults per-code policies
+// first and falls back to base for any code without an explicit policy.
+// attempts counts attempts for the single export operation the returned
+// classifier is used with; it must not be shared across concurrent
+// operations.
+func policyClassifier(base RetryClassifier, policies map[codes.Code]RetryPolicy) RetryClassifier {
+	if len(policies) == 0 {
+		return base
+	}
+
+	attempts := make(map[codes.Code]int, len(policies))
+	return func(err error) (bool, time.Duration) {
+		code := statusCode(err)
+		policy, ok := policies[code]
+		if !ok {
+			return base(err)
+		}
+
+		if !policy.Retry {
+			return false, 0
+		}
+
+		attempts[code]++
+		if policy.MaxAttempts > 0 && attempts[code] >= policy.MaxAttempts {
+			return false, 0
+		}
+
+		// policy.Retry == true is authoritative here: base is consulted only
+		// for its throttle delay, not for whether to retry at all. Otherwise
+		// a policy could never force a retry for a code base treats as
+		// fatal, which defeats the point of overriding it per code. A base
+		// that treats the code as fatal reports no delay, so the RetryInfo
+		// hint is then read from the status directly.
+		baseRetry, delay := base(err)
+		if !baseRetry {
+			delay = throttleDelay(status.Convert(err))
+		}
+		if policy.Multiplier > 0 {
+			delay = time.Duration(float64(delay) * policy.Multiplier)
+		}
+		return true, delay
+	}
+}
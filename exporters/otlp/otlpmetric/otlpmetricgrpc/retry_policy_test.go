@@ -0,0 +1,105 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlpmetricgrpc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func alwaysRetry(err error) (bool, time.Duration) { return true, 0 }
+
+func withThrottle(d time.Duration) RetryClassifier {
+	return func(err error) (bool, time.Duration) { return true, d }
+}
+
+func TestPolicyClassifierNoPolicies(t *testing.T) {
+	c := policyClassifier(retryable, nil)
+	retry, _ := c(status.Error(codes.Unavailable, "boom"))
+	assert.True(t, retry)
+}
+
+func TestPolicyClassifierFallsBackForUnlistedCode(t *testing.T) {
+	policies := map[codes.Code]RetryPolicy{
+		codes.ResourceExhausted: {Retry: true},
+	}
+	c := policyClassifier(retryable, policies)
+
+	retry, _ := c(status.Error(codes.Unavailable, "boom"))
+	assert.True(t, retry, "codes without a policy should use the base classifier")
+}
+
+func TestPolicyClassifierCanMarkCodeFatal(t *testing.T) {
+	policies := map[codes.Code]RetryPolicy{
+		codes.Canceled: {Retry: false},
+	}
+	c := policyClassifier(alwaysRetry, policies)
+
+	retry, delay := c(status.Error(codes.Canceled, "boom"))
+	assert.False(t, retry)
+	assert.Zero(t, delay)
+}
+
+func TestPolicyClassifierEnforcesMaxAttempts(t *testing.T) {
+	policies := map[codes.Code]RetryPolicy{
+		codes.Unavailable: {Retry: true, MaxAttempts: 2},
+	}
+	c := policyClassifier(alwaysRetry, policies)
+	err := status.Error(codes.Unavailable, "boom")
+
+	retry, _ := c(err) // attempt 1
+	assert.True(t, retry)
+
+	retry, _ = c(err) // attempt 2, hits MaxAttempts
+	assert.False(t, retry)
+}
+
+func TestPolicyClassifierScalesThrottleDelay(t *testing.T) {
+	policies := map[codes.Code]RetryPolicy{
+		codes.ResourceExhausted: {Retry: true, Multiplier: 2},
+	}
+	c := policyClassifier(withThrottle(100*time.Millisecond), policies)
+
+	_, delay := c(status.Error(codes.ResourceExhausted, "boom"))
+	assert.Equal(t, 200*time.Millisecond, delay)
+}
+
+func TestPolicyClassifierForcesRetryForCodeBaseTreatsAsFatal(t *testing.T) {
+	policies := map[codes.Code]RetryPolicy{
+		codes.Internal: {Retry: true},
+	}
+	c := policyClassifier(retryable, policies)
+
+	retry, _ := c(status.Error(codes.Internal, "boom"))
+	assert.True(t, retry, "a policy's Retry: true must override the base classifier, not just the throttle delay")
+}
+
+func TestPolicyClassifierAttemptsAreIsolatedPerCode(t *testing.T) {
+	policies := map[codes.Code]RetryPolicy{
+		codes.Unavailable:       {Retry: true, MaxAttempts: 1},
+		codes.ResourceExhausted: {Retry: true, MaxAttempts: 5},
+	}
+	c := policyClassifier(alwaysRetry, policies)
+
+	retry, _ := c(status.Error(codes.Unavailable, "boom"))
+	assert.False(t, retry, "MaxAttempts of 1 rejects on the first attempt")
+
+	retry, _ = c(status.Error(codes.ResourceExhausted, "boom"))
+	assert.True(t, retry, "a different code's attempt count must not be shared")
+}